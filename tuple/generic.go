@@ -0,0 +1,76 @@
+package tuple
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nwca/hidalgo/types"
+)
+
+// compareKey compares two keys lexicographically, field by field, the same
+// order Table.Scan already returns tuples in. A shorter key that is a prefix
+// of a longer one sorts first.
+func compareKey(a, b Key) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareSortable(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// compareSortable compares two values of the same concrete Sortable type, as
+// used for all of hidalgo's built-in types.Type implementations.
+func compareSortable(a, b types.Sortable) int {
+	switch av := a.(type) {
+	case types.String:
+		return strings.Compare(string(av), string(b.(types.String)))
+	case types.Bytes:
+		return bytes.Compare([]byte(av), []byte(b.(types.Bytes)))
+	case types.Int:
+		bv := b.(types.Int)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		default:
+			return 0
+		}
+	case types.UInt:
+		bv := b.(types.UInt)
+		switch {
+		case av < bv:
+			return -1
+		case av > bv:
+			return 1
+		default:
+			return 0
+		}
+	case types.Bool:
+		bv := b.(types.Bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		default:
+			return 1
+		}
+	case types.Time:
+		bv := time.Time(b.(types.Time))
+		at := time.Time(av)
+		switch {
+		case at.Before(bv):
+			return -1
+		case at.After(bv):
+			return 1
+		default:
+			return 0
+		}
+	default:
+		panic(fmt.Sprintf("tuple: unsupported sortable type in comparison: %T", a))
+	}
+}
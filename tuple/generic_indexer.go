@@ -0,0 +1,275 @@
+package tuple
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nwca/hidalgo/types"
+)
+
+// NewGenericIndexer wraps tbl so it additionally implements Indexer,
+// maintaining each index as an auxiliary table created on tx. tupletest
+// falls back to it for tables that do not implement Indexer; nothing in
+// tuple/kv calls it, so a tuple/kv table has secondary indexes only when a
+// caller wraps it this way.
+//
+// The auxiliary table for an index is keyed by the index's KeyFields (if
+// any) followed by its Fields, followed by h's full primary key, with an
+// empty payload, exactly as described for a native implementation.
+func NewGenericIndexer(tx Tx, h Header, tbl Table) Table {
+	return &genericIndexer{tx: tx, h: h, Table: tbl, aux: map[string]*indexHandle{}}
+}
+
+type indexHandle struct {
+	def      IndexDef
+	tbl      Table
+	keyIdx   []int // indices into h.Key, from def.KeyFields
+	fieldIdx []int // indices into h.Data, from def.Fields
+}
+
+type genericIndexer struct {
+	Table
+	tx  Tx
+	h   Header
+	aux map[string]*indexHandle
+}
+
+func fieldIndex(names []string, by func(string) int) ([]int, error) {
+	idx := make([]int, len(names))
+	for i, name := range names {
+		j := by(name)
+		if j < 0 {
+			return nil, fmt.Errorf("unknown field %q", name)
+		}
+		idx[i] = j
+	}
+	return idx, nil
+}
+
+func (g *genericIndexer) CreateIndex(ctx context.Context, idx IndexDef) error {
+	keyIdx, err := fieldIndex(idx.KeyFields, func(name string) int {
+		for i, kf := range g.h.Key {
+			if kf.Name == name {
+				return i
+			}
+		}
+		return -1
+	})
+	if err != nil {
+		return err
+	}
+	fieldIdx, err := fieldIndex(idx.Fields, func(name string) int {
+		for i, f := range g.h.Data {
+			if f.Name == name {
+				return i
+			}
+		}
+		return -1
+	})
+	if err != nil {
+		return err
+	}
+
+	auxHeader := Header{Name: g.h.Name + "$idx$" + idx.Name}
+	for _, i := range keyIdx {
+		auxHeader.Key = append(auxHeader.Key, g.h.Key[i])
+	}
+	for _, i := range fieldIdx {
+		kf := g.h.Data[i]
+		auxHeader.Key = append(auxHeader.Key, KeyField{Name: kf.Name, Type: kf.Type})
+	}
+	for _, kf := range g.h.Key {
+		auxHeader.Key = append(auxHeader.Key, KeyField{Name: "$pk$" + kf.Name, Type: kf.Type})
+	}
+
+	auxTbl, err := g.tx.CreateTable(ctx, auxHeader)
+	if err != nil {
+		return fmt.Errorf("index %q: %v", idx.Name, err)
+	}
+	h := &indexHandle{def: idx, tbl: auxTbl, keyIdx: keyIdx, fieldIdx: fieldIdx}
+	g.aux[idx.Name] = h
+
+	it := g.Table.Scan(nil)
+	defer it.Close()
+	for it.Next(ctx) {
+		if err := g.indexInsert(ctx, h, it.Key(), it.Data()); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (h *indexHandle) auxKey(key Key, data Data) (Key, error) {
+	k := make(Key, 0, len(h.keyIdx)+len(h.fieldIdx)+len(key))
+	for _, i := range h.keyIdx {
+		k = append(k, key[i])
+	}
+	for _, i := range h.fieldIdx {
+		sv, ok := data[i].(types.Sortable)
+		if !ok {
+			return nil, fmt.Errorf("field %q is not sortable, cannot be indexed", h.def.Fields[indexOf(h.fieldIdx, i)])
+		}
+		k = append(k, sv)
+	}
+	return append(k, key...), nil
+}
+
+func indexOf(s []int, v int) int {
+	for i, x := range s {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// indexValuePrefix is the leading portion of an aux key shared by every row
+// with the same indexed values, i.e. everything but the primary key suffix.
+func (h *indexHandle) indexValuePrefix(auxKey Key) Key {
+	return auxKey[:len(h.keyIdx)+len(h.fieldIdx)]
+}
+
+func (g *genericIndexer) indexInsert(ctx context.Context, h *indexHandle, key Key, data Data) error {
+	auxKey, err := h.auxKey(key, data)
+	if err != nil {
+		return err
+	}
+	if h.def.Unique {
+		it := h.tbl.Scan(h.indexValuePrefix(auxKey))
+		has := it.Next(ctx)
+		err := it.Err()
+		it.Close()
+		if err != nil {
+			return err
+		}
+		if has {
+			return fmt.Errorf("index %q: unique constraint violated", h.def.Name)
+		}
+	}
+	_, err = h.tbl.InsertTuple(ctx, Tuple{Key: auxKey})
+	return err
+}
+
+func (g *genericIndexer) indexDelete(ctx context.Context, h *indexHandle, key Key, data Data) error {
+	auxKey, err := h.auxKey(key, data)
+	if err != nil {
+		return err
+	}
+	return h.tbl.DeleteTuple(ctx, auxKey)
+}
+
+// InsertTuple inserts into the primary table first, and only maintains
+// indexes once that succeeds, so a rejected primary insert (e.g. a
+// duplicate primary key) never leaves an orphaned index entry pointing at a
+// row that was never written.
+func (g *genericIndexer) InsertTuple(ctx context.Context, tup Tuple) (Key, error) {
+	key, err := g.Table.InsertTuple(ctx, tup)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range g.aux {
+		if err := g.indexInsert(ctx, h, tup.Key, tup.Data); err != nil {
+			return key, err
+		}
+	}
+	return key, nil
+}
+
+// UpdateTuple reads the old row (if any) up front, but only moves index
+// entries after the primary update has actually succeeded, for the same
+// reason InsertTuple inserts before indexing.
+func (g *genericIndexer) UpdateTuple(ctx context.Context, tup Tuple, fields []string) error {
+	old, err := g.Table.GetTuple(ctx, tup.Key)
+	hadOld := err == nil
+
+	if err := g.Table.UpdateTuple(ctx, tup, fields); err != nil {
+		return err
+	}
+
+	for _, h := range g.aux {
+		if hadOld {
+			if err := g.indexDelete(ctx, h, tup.Key, old); err != nil {
+				return err
+			}
+		}
+		if err := g.indexInsert(ctx, h, tup.Key, tup.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTuple reads the old row up front so its indexed values are still
+// known, but only removes index entries once the primary delete has
+// actually succeeded.
+func (g *genericIndexer) DeleteTuple(ctx context.Context, key Key) error {
+	old, err := g.Table.GetTuple(ctx, key)
+	hadOld := err == nil
+
+	if err := g.Table.DeleteTuple(ctx, key); err != nil {
+		return err
+	}
+
+	if hadOld {
+		for _, h := range g.aux {
+			if err := g.indexDelete(ctx, h, key, old); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *genericIndexer) ScanIndex(name string, prefix Key) Iterator {
+	h, ok := g.aux[name]
+	if !ok {
+		return errIterator{err: fmt.Errorf("unknown index %q", name)}
+	}
+	return &indexIterator{h: h, primary: g.Table, src: h.tbl.Scan(prefix)}
+}
+
+// indexIterator walks an index's auxiliary table and resolves each entry
+// back to the full tuple stored under its primary key.
+type indexIterator struct {
+	h       *indexHandle
+	primary Table
+	src     Iterator
+	key     Key
+	data    Data
+	err     error
+}
+
+func (it *indexIterator) Next(ctx context.Context) bool {
+	if !it.src.Next(ctx) {
+		it.err = it.src.Err()
+		return false
+	}
+	auxKey := it.src.Key()
+	key := auxKey[len(it.h.keyIdx)+len(it.h.fieldIdx):]
+	data, err := it.primary.GetTuple(ctx, key)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.key, it.data = key, data
+	return true
+}
+
+func (it *indexIterator) Key() Key     { return it.key }
+func (it *indexIterator) Data() Data   { return it.data }
+func (it *indexIterator) Err() error   { return it.err }
+func (it *indexIterator) Close() error { return it.src.Close() }
+
+// errIterator is an Iterator that immediately fails with err, for reporting
+// usage errors (e.g. ScanIndex for an index that was never created) through
+// the same interface as a real scan instead of panicking.
+type errIterator struct{ err error }
+
+func (errIterator) Next(context.Context) bool { return false }
+func (errIterator) Key() Key                  { return nil }
+func (errIterator) Data() Data                { return nil }
+func (it errIterator) Err() error             { return it.err }
+func (errIterator) Close() error              { return nil }
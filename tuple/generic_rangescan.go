@@ -0,0 +1,96 @@
+package tuple
+
+import "context"
+
+// GenericRangeScan adapts any Table's plain prefix Scan into a RangeScanner
+// by buffering and filtering in memory. tupletest falls back to it for
+// tables that do not implement RangeScanner; nothing in tuple/kv calls it,
+// so a tuple/kv table supports ScanRange only when a caller wraps it this
+// way. Bounds are not translated into the backend's seek+iterate
+// primitives, and keys are compared with compareKey, which supports only
+// hidalgo's built-in sortable types and panics on any other.
+func GenericRangeScan(tbl Table) RangeScanner {
+	return genericRangeScanner{tbl: tbl}
+}
+
+type genericRangeScanner struct {
+	tbl Table
+}
+
+func (g genericRangeScanner) ScanRange(opts ScanOptions) Iterator {
+	return &rangeScanIterator{opts: opts, src: g.tbl.Scan(opts.Prefix)}
+}
+
+// rangeScanIterator drains its source prefix scan into a filtered, possibly
+// reversed buffer on first use, then serves Key/Data off that buffer. The
+// source is sorted ascending, which lets it stop early once it passes End or
+// (in the non-reverse case) reaches Limit.
+type rangeScanIterator struct {
+	opts   ScanOptions
+	src    Iterator
+	buf    []Tuple
+	pos    int
+	primed bool
+	err    error
+}
+
+func (it *rangeScanIterator) prime(ctx context.Context) {
+	if it.primed {
+		return
+	}
+	it.primed = true
+	defer it.src.Close()
+
+	for it.src.Next(ctx) {
+		k := it.src.Key()
+		if it.opts.Start != nil {
+			c := compareKey(k, it.opts.Start)
+			if c < 0 || (c == 0 && it.opts.StartExclusive) {
+				continue
+			}
+		}
+		if it.opts.End != nil {
+			c := compareKey(k, it.opts.End)
+			if c > 0 || (c == 0 && it.opts.EndExclusive) {
+				break
+			}
+		}
+
+		it.buf = append(it.buf, Tuple{
+			Key:  append(Key{}, k...),
+			Data: append(Data{}, it.src.Data()...),
+		})
+		if it.opts.Limit > 0 && !it.opts.Reverse && len(it.buf) >= it.opts.Limit {
+			break
+		}
+	}
+	it.err = it.src.Err()
+
+	if it.opts.Reverse {
+		for l, r := 0, len(it.buf)-1; l < r; l, r = l+1, r-1 {
+			it.buf[l], it.buf[r] = it.buf[r], it.buf[l]
+		}
+		if it.opts.Limit > 0 && len(it.buf) > it.opts.Limit {
+			it.buf = it.buf[:it.opts.Limit]
+		}
+	}
+}
+
+func (it *rangeScanIterator) Next(ctx context.Context) bool {
+	it.prime(ctx)
+	if it.err != nil || it.pos >= len(it.buf) {
+		return false
+	}
+	it.pos++
+	return true
+}
+
+func (it *rangeScanIterator) Key() Key   { return it.buf[it.pos-1].Key }
+func (it *rangeScanIterator) Data() Data { return it.buf[it.pos-1].Data }
+func (it *rangeScanIterator) Err() error { return it.err }
+func (it *rangeScanIterator) Close() error {
+	if !it.primed {
+		return it.src.Close()
+	}
+	return nil
+}
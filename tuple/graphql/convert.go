@@ -0,0 +1,104 @@
+package graphql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/nwca/hidalgo/types"
+)
+
+// graphqlType maps a tuple field type to the GraphQL output type used to
+// represent it.
+func graphqlType(t types.Type) (graphql.Output, error) {
+	switch t.(type) {
+	case types.StringType:
+		return graphql.String, nil
+	case types.BytesType:
+		return bytesScalar, nil
+	case types.IntType:
+		return graphql.Int, nil
+	case types.UIntType:
+		return graphql.Int, nil
+	case types.BoolType:
+		return graphql.Boolean, nil
+	case types.TimeType:
+		return timeScalar, nil
+	default:
+		return nil, fmt.Errorf("unsupported field type: %T", t)
+	}
+}
+
+// toValue converts a resolved GraphQL argument into the types.Value expected
+// by the given field type.
+func toValue(t types.Type, v interface{}) (types.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	switch t.(type) {
+	case types.StringType:
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string, got %T", v)
+		}
+		return types.String(s), nil
+	case types.BytesType:
+		b, ok := v.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("expected bytes, got %T", v)
+		}
+		return types.Bytes(b), nil
+	case types.IntType:
+		n, ok := v.(int)
+		if !ok {
+			return nil, fmt.Errorf("expected int, got %T", v)
+		}
+		return types.Int(n), nil
+	case types.UIntType:
+		n, ok := v.(int)
+		if !ok {
+			return nil, fmt.Errorf("expected int, got %T", v)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("expected non-negative int, got %d", n)
+		}
+		return types.UInt(n), nil
+	case types.BoolType:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected bool, got %T", v)
+		}
+		return types.Bool(b), nil
+	case types.TimeType:
+		tm, ok := v.(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("expected time, got %T", v)
+		}
+		return types.Time(tm), nil
+	default:
+		return nil, fmt.Errorf("unsupported field type: %T", t)
+	}
+}
+
+// fromValue converts a types.Value read from storage into the representation
+// expected by the graphql-go resolver output (and, in turn, its scalar's
+// Serialize function).
+func fromValue(v types.Value) interface{} {
+	switch v := v.(type) {
+	case types.String:
+		return string(v)
+	case types.Bytes:
+		return []byte(v)
+	case types.Int:
+		return int(v)
+	case types.UInt:
+		return int(v)
+	case types.Bool:
+		return bool(v)
+	case types.Time:
+		return time.Time(v)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+// Package graphql exposes any tuple.Store as a GraphQL API.
+//
+// Each tuple.Header registered with the handler becomes a GraphQL object
+// type: KeyFields form the primary-key argument set for single-item lookups
+// and mutations, and Fields become the object's own fields. List queries are
+// backed by Table.Scan with a prefix built from the supplied key arguments,
+// while mutations are mapped directly onto InsertTuple, UpdateTuple and
+// DeleteTuple.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/nwca/hidalgo/tuple"
+)
+
+// Handler serves GraphQL queries and mutations against a tuple.Store.
+type Handler struct {
+	store  tuple.Store
+	schema graphql.Schema
+}
+
+// NewHandler builds a GraphQL schema from the given headers and returns an
+// http.Handler that executes queries and mutations against store.
+//
+// The store must already contain a table for each header (see
+// Tx.CreateTable); NewHandler only builds the schema, it does not create
+// tables.
+func NewHandler(store tuple.Store, headers ...tuple.Header) (*Handler, error) {
+	if len(headers) == 0 {
+		return nil, fmt.Errorf("graphql: no tables specified")
+	}
+	schema, err := newSchema(store, headers)
+	if err != nil {
+		return nil, fmt.Errorf("graphql: %v", err)
+	}
+	return &Handler{store: store, schema: schema}, nil
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.Exec(r.Context(), w, req.Query, req.OperationName, req.Variables)
+}
+
+// Exec runs a single GraphQL query or mutation and writes the JSON-encoded
+// result to w. It is exposed separately from ServeHTTP so callers can wire
+// it into their own transport (e.g. an in-process test harness).
+func (h *Handler) Exec(ctx context.Context, w http.ResponseWriter, query, op string, vars map[string]interface{}) {
+	res := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  query,
+		OperationName:  op,
+		VariableValues: vars,
+		Context:        ctx,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
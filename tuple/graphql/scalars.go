@@ -0,0 +1,80 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// bytesScalar encodes/decodes tuple byte fields as base64 strings, since
+// GraphQL has no native binary scalar.
+var bytesScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Bytes",
+	Description: "Base64-encoded byte string",
+	Serialize: func(v interface{}) interface{} {
+		b, ok := v.([]byte)
+		if !ok {
+			return nil
+		}
+		return base64.StdEncoding.EncodeToString(b)
+	},
+	ParseValue: func(v interface{}) interface{} {
+		s, ok := v.(string)
+		if !ok {
+			return nil
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil
+		}
+		return b
+	},
+	ParseLiteral: func(v ast.Value) interface{} {
+		s, ok := v.(*ast.StringValue)
+		if !ok {
+			return nil
+		}
+		b, err := base64.StdEncoding.DecodeString(s.Value)
+		if err != nil {
+			return nil
+		}
+		return b
+	},
+})
+
+// timeScalar serializes time.Time fields as RFC3339 strings.
+var timeScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Time",
+	Description: "RFC3339 timestamp",
+	Serialize: func(v interface{}) interface{} {
+		t, ok := v.(time.Time)
+		if !ok {
+			return nil
+		}
+		return t.Format(time.RFC3339Nano)
+	},
+	ParseValue: func(v interface{}) interface{} {
+		s, ok := v.(string)
+		if !ok {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil
+		}
+		return t
+	},
+	ParseLiteral: func(v ast.Value) interface{} {
+		s, ok := v.(*ast.StringValue)
+		if !ok {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, s.Value)
+		if err != nil {
+			return nil
+		}
+		return t
+	},
+})
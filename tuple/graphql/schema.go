@@ -0,0 +1,320 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/nwca/hidalgo/tuple"
+	"github.com/nwca/hidalgo/types"
+)
+
+// newSchema builds a graphql.Schema exposing one query/mutation group per
+// header, resolved against store.
+func newSchema(store tuple.Store, headers []tuple.Header) (graphql.Schema, error) {
+	queryFields := graphql.Fields{}
+	mutationFields := graphql.Fields{}
+
+	for _, h := range headers {
+		obj, err := objectType(h)
+		if err != nil {
+			return graphql.Schema{}, fmt.Errorf("table %q: %v", h.Name, err)
+		}
+		keyArgs, err := keyArgs(h)
+		if err != nil {
+			return graphql.Schema{}, fmt.Errorf("table %q: %v", h.Name, err)
+		}
+		dataArgs, err := dataArgs(h)
+		if err != nil {
+			return graphql.Schema{}, fmt.Errorf("table %q: %v", h.Name, err)
+		}
+
+		queryFields[h.Name] = &graphql.Field{
+			Type:    obj,
+			Args:    keyArgs,
+			Resolve: getResolver(store, h),
+		}
+		queryFields[h.Name+"List"] = &graphql.Field{
+			Type:    graphql.NewList(obj),
+			Args:    prefixArgs(h),
+			Resolve: listResolver(store, h),
+		}
+
+		insertArgs := graphql.FieldConfigArgument{}
+		for k, v := range keyArgs {
+			insertArgs[k] = v
+		}
+		for k, v := range dataArgs {
+			insertArgs[k] = v
+		}
+		mutationFields["insert"+h.Name] = &graphql.Field{
+			Type:    graphql.Boolean,
+			Args:    insertArgs,
+			Resolve: insertResolver(store, h),
+		}
+		mutationFields["update"+h.Name] = &graphql.Field{
+			Type:    graphql.Boolean,
+			Args:    insertArgs,
+			Resolve: updateResolver(store, h),
+		}
+		mutationFields["delete"+h.Name] = &graphql.Field{
+			Type:    graphql.Boolean,
+			Args:    keyArgs,
+			Resolve: deleteResolver(store, h),
+		}
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: queryFields})
+	mutation := graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: mutationFields})
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// objectType builds the GraphQL object type for a table, with one field per
+// key field and per data field.
+func objectType(h tuple.Header) (*graphql.Object, error) {
+	fields := graphql.Fields{}
+	for _, kf := range h.Key {
+		t, err := graphqlType(kf.Type.SortableType())
+		if err != nil {
+			return nil, fmt.Errorf("key field %q: %v", kf.Name, err)
+		}
+		fields[kf.Name] = &graphql.Field{Type: t}
+	}
+	for _, f := range h.Data {
+		t, err := graphqlType(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", f.Name, err)
+		}
+		fields[f.Name] = &graphql.Field{Type: t}
+	}
+	return graphql.NewObject(graphql.ObjectConfig{Name: h.Name, Fields: fields}), nil
+}
+
+func keyArgs(h tuple.Header) (graphql.FieldConfigArgument, error) {
+	args := graphql.FieldConfigArgument{}
+	for _, kf := range h.Key {
+		t, err := graphqlType(kf.Type.SortableType())
+		if err != nil {
+			return nil, err
+		}
+		args[kf.Name] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(t)}
+	}
+	return args, nil
+}
+
+// prefixArgs is like keyArgs but every key field is optional, since a list
+// query may supply only a prefix of the primary key.
+func prefixArgs(h tuple.Header) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{}
+	for _, kf := range h.Key {
+		t, err := graphqlType(kf.Type.SortableType())
+		if err != nil {
+			continue
+		}
+		args[kf.Name] = &graphql.ArgumentConfig{Type: t}
+	}
+	return args
+}
+
+func dataArgs(h tuple.Header) (graphql.FieldConfigArgument, error) {
+	args := graphql.FieldConfigArgument{}
+	for _, f := range h.Data {
+		t, err := graphqlType(f.Type)
+		if err != nil {
+			return nil, err
+		}
+		args[f.Name] = &graphql.ArgumentConfig{Type: t}
+	}
+	return args, nil
+}
+
+func tupleToMap(h tuple.Header, tup tuple.Tuple) map[string]interface{} {
+	out := make(map[string]interface{}, len(h.Key)+len(h.Data))
+	for i, kf := range h.Key {
+		out[kf.Name] = fromValue(tup.Key[i])
+	}
+	for i, f := range h.Data {
+		out[f.Name] = fromValue(tup.Data[i])
+	}
+	return out
+}
+
+// buildKey converts the resolved primary-key arguments into a tuple.Key.
+// It returns an error if any key field argument is missing, which Query and
+// Mutation fields enforce via graphql.NewNonNull on keyArgs.
+func buildKey(h tuple.Header, args map[string]interface{}) (tuple.Key, error) {
+	var key tuple.Key
+	for _, kf := range h.Key {
+		v, err := toValue(kf.Type.SortableType(), args[kf.Name])
+		if err != nil {
+			return nil, fmt.Errorf("key field %q: %v", kf.Name, err)
+		}
+		sv, ok := v.(types.Sortable)
+		if !ok {
+			return nil, fmt.Errorf("key field %q: %T is not sortable", kf.Name, v)
+		}
+		key = append(key, sv)
+	}
+	return key, nil
+}
+
+// buildPrefix is like buildKey, but stops at the first key field that was
+// not supplied, yielding a prefix suitable for Table.Scan.
+func buildPrefix(h tuple.Header, args map[string]interface{}) (tuple.Key, error) {
+	var key tuple.Key
+	for _, kf := range h.Key {
+		raw, ok := args[kf.Name]
+		if !ok || raw == nil {
+			break
+		}
+		v, err := toValue(kf.Type.SortableType(), raw)
+		if err != nil {
+			return nil, fmt.Errorf("key field %q: %v", kf.Name, err)
+		}
+		sv, ok := v.(types.Sortable)
+		if !ok {
+			return nil, fmt.Errorf("key field %q: %T is not sortable", kf.Name, v)
+		}
+		key = append(key, sv)
+	}
+	return key, nil
+}
+
+func buildData(h tuple.Header, args map[string]interface{}) (tuple.Data, error) {
+	var data tuple.Data
+	for _, f := range h.Data {
+		v, err := toValue(f.Type, args[f.Name])
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", f.Name, err)
+		}
+		data = append(data, v)
+	}
+	return data, nil
+}
+
+func getResolver(store tuple.Store, h tuple.Header) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		key, err := buildKey(h, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := store.Tx(false)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Close()
+		tbl, err := tx.Table(p.Context, h.Name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := tbl.GetTuple(p.Context, key)
+		if err != nil {
+			return nil, err
+		}
+		return tupleToMap(h, tuple.Tuple{Key: key, Data: data}), nil
+	}
+}
+
+func listResolver(store tuple.Store, h tuple.Header) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		prefix, err := buildPrefix(h, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		ctx := p.Context
+		tx, err := store.Tx(false)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Close()
+		tbl, err := tx.Table(ctx, h.Name)
+		if err != nil {
+			return nil, err
+		}
+		it := tbl.Scan(prefix)
+		defer it.Close()
+
+		var out []map[string]interface{}
+		for it.Next(ctx) {
+			out = append(out, tupleToMap(h, tuple.Tuple{Key: it.Key(), Data: it.Data()}))
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+}
+
+func insertResolver(store tuple.Store, h tuple.Header) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return mutate(store, h, p, func(tbl tuple.Table, ctx context.Context, tup tuple.Tuple) error {
+			_, err := tbl.InsertTuple(ctx, tup)
+			return err
+		})
+	}
+}
+
+func updateResolver(store tuple.Store, h tuple.Header) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return mutate(store, h, p, func(tbl tuple.Table, ctx context.Context, tup tuple.Tuple) error {
+			return tbl.UpdateTuple(ctx, tup, nil)
+		})
+	}
+}
+
+func deleteResolver(store tuple.Store, h tuple.Header) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		key, err := buildKey(h, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		ctx := p.Context
+		tx, err := store.Tx(true)
+		if err != nil {
+			return nil, err
+		}
+		tbl, err := tx.Table(ctx, h.Name)
+		if err != nil {
+			tx.Close()
+			return nil, err
+		}
+		if err := tbl.DeleteTuple(ctx, key); err != nil {
+			tx.Close()
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
+}
+
+func mutate(store tuple.Store, h tuple.Header, p graphql.ResolveParams, fn func(tuple.Table, context.Context, tuple.Tuple) error) (interface{}, error) {
+	key, err := buildKey(h, p.Args)
+	if err != nil {
+		return nil, err
+	}
+	data, err := buildData(h, p.Args)
+	if err != nil {
+		return nil, err
+	}
+	ctx := p.Context
+	tx, err := store.Tx(true)
+	if err != nil {
+		return nil, err
+	}
+	tbl, err := tx.Table(ctx, h.Name)
+	if err != nil {
+		tx.Close()
+		return nil, err
+	}
+	if err := fn(tbl, ctx, tuple.Tuple{Key: key, Data: data}); err != nil {
+		tx.Close()
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
@@ -0,0 +1,34 @@
+package tuple
+
+import "context"
+
+// IndexDef describes a secondary index over one or more of a table's Data
+// fields (and, optionally, a subset of its key fields). Indexes are
+// maintained as auxiliary tables keyed by the indexed values followed by the
+// primary key, with empty payloads, kept in sync transactionally on
+// InsertTuple, UpdateTuple and DeleteTuple.
+type IndexDef struct {
+	// Name identifies the index for ScanIndex.
+	Name string
+	// Fields lists the Data field names to index, in index key order.
+	Fields []string
+	// KeyFields optionally lists a subset of the table's KeyFields to
+	// prepend to Fields in the index key, before the full primary key.
+	KeyFields []string
+	// Unique rejects InsertTuple/UpdateTuple calls that would produce a
+	// duplicate combination of indexed values.
+	Unique bool
+}
+
+// Indexer is implemented by tables that support secondary indexes in
+// addition to their primary key. Not every backend implements Indexer;
+// callers should type-assert for it.
+type Indexer interface {
+	// CreateIndex registers a secondary index and backfills it from the
+	// table's current contents.
+	CreateIndex(ctx context.Context, idx IndexDef) error
+	// ScanIndex iterates tuples in the order of the named index, optionally
+	// restricted to a prefix of its indexed values. The returned Iterator
+	// yields full tuples (key and data), not just the index entries.
+	ScanIndex(name string, prefix Key) Iterator
+}
@@ -0,0 +1,29 @@
+package tuple
+
+// Isolation describes the consistency guarantee a Store provides to
+// concurrent transactions.
+type Isolation int
+
+const (
+	// IsolationSerializable guarantees that conflicting concurrent writes
+	// are serialized: at most one of them commits, and the losing
+	// transaction's Commit returns an error. This is the strictest level,
+	// and the one assumed of stores that don't implement IsolationLevel.
+	IsolationSerializable Isolation = iota
+	// IsolationSnapshot guarantees each transaction sees a consistent
+	// snapshot of the store as of its start. Concurrent writes to the same
+	// key still conflict: the first to commit wins, and every later
+	// transaction's Commit fails, exactly as under IsolationSerializable.
+	// What IsolationSnapshot relaxes relative to IsolationSerializable is
+	// read/write conflicts between non-overlapping keys, not same-key
+	// write conflicts.
+	IsolationSnapshot
+)
+
+// IsolationLevel is implemented by stores that can report the isolation
+// level they provide to concurrent transactions. tupletest's concurrency
+// subtest does not consult it: its contended-writes assertion is the same
+// for both levels.
+type IsolationLevel interface {
+	Isolation() Isolation
+}
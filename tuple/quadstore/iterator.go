@@ -0,0 +1,251 @@
+package quadstore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/cayleygraph/cayley/graph"
+	"github.com/cayleygraph/cayley/graph/iterator"
+	"github.com/cayleygraph/quad"
+
+	"github.com/nwca/hidalgo/tuple"
+	"github.com/nwca/hidalgo/types"
+)
+
+// quadIteratorType identifies quadIterator to Cayley's query optimizer.
+var quadIteratorType = graph.RegisterIterator("hidalgo-tuple")
+
+// tableFor returns the index table whose leading key field is d, along with
+// the order in which that table's key fields map back onto quad directions.
+// Label has no dedicated index (it is low-cardinality in most graphs), so it
+// is served off the SPO table with an in-memory filter.
+func tableFor(d quad.Direction) (table string, order [4]quad.Direction) {
+	switch d {
+	case quad.Predicate:
+		return TablePOS, [4]quad.Direction{quad.Predicate, quad.Object, quad.Subject, quad.Label}
+	case quad.Object:
+		return TableOSP, [4]quad.Direction{quad.Object, quad.Subject, quad.Predicate, quad.Label}
+	default:
+		return TableSPO, [4]quad.Direction{quad.Subject, quad.Predicate, quad.Object, quad.Label}
+	}
+}
+
+func toSortable(d quad.Direction, v quad.Value) types.Sortable {
+	s := quad.StringOf(v)
+	if d == quad.Object {
+		return types.Bytes(s)
+	}
+	return types.String(s)
+}
+
+func valueFor(d quad.Direction, v types.Sortable) quad.Value {
+	if d == quad.Object {
+		return quad.Raw(string(v.(types.Bytes)))
+	}
+	s := string(v.(types.String))
+	if d == quad.Label && s == "" {
+		return nil
+	}
+	return quad.Raw(s)
+}
+
+// quadIterator walks a prefix scan of one of the SPO/POS/OSP tables and
+// reconstructs quad.Quad values from the key fields in table order. It
+// embeds iterator.Base for the Tagger/TagResults/UID bookkeeping every
+// Cayley iterator needs, the same way concrete backends under legacy/nosql
+// do, and opens its underlying tuple.Tx lazily so that Clone is cheap.
+type quadIterator struct {
+	iterator.Base
+
+	qs    *QuadStore
+	table string
+	order [4]quad.Direction
+
+	// filter/want hold the in-memory label fallback: when set, only quads
+	// whose direction filter equals want are surfaced.
+	filter quad.Direction
+	want   quad.Value
+	// prefix is the scan prefix for the chosen table; nil for a full scan.
+	prefix tuple.Key
+
+	tx  tuple.Tx
+	src tuple.Iterator
+	cur quad.Quad
+	err error
+}
+
+func newAllIterator(qs *QuadStore, nodes bool) graph.Iterator {
+	table, order := tableFor(quad.Subject)
+	qi := newQuadIteratorOn(qs, table, order, nil, 0, nil)
+	if nodes {
+		return iterator.NewUnique(qi)
+	}
+	return qi
+}
+
+func newDirectionIterator(qs *QuadStore, d quad.Direction, v quadValue) *quadIterator {
+	if d == quad.Label {
+		table, order := tableFor(quad.Subject)
+		return newQuadIteratorOn(qs, table, order, nil, quad.Label, v.v)
+	}
+	table, order := tableFor(d)
+	prefix := tuple.Key{toSortable(d, v.v)}
+	return newQuadIteratorOn(qs, table, order, prefix, 0, nil)
+}
+
+func newQuadIteratorOn(qs *QuadStore, table string, order [4]quad.Direction, prefix tuple.Key, filter quad.Direction, want quad.Value) *quadIterator {
+	return &quadIterator{
+		qs: qs, table: table, order: order,
+		prefix: prefix, filter: filter, want: want,
+	}
+}
+
+func (it *quadIterator) open(ctx context.Context) error {
+	if it.src != nil || it.err != nil {
+		return it.err
+	}
+	tx, err := it.qs.store.Tx(false)
+	if err != nil {
+		it.err = err
+		return err
+	}
+	tbl, err := tx.Table(ctx, it.table)
+	if err != nil {
+		tx.Close()
+		it.err = err
+		return err
+	}
+	it.tx = tx
+	it.src = tbl.Scan(it.prefix)
+	return nil
+}
+
+func (it *quadIterator) quadAt() quad.Quad {
+	key := it.src.Key()
+	var q quad.Quad
+	for i, d := range it.order {
+		q.Set(d, valueFor(d, key[i].(types.Sortable)))
+	}
+	return q
+}
+
+// Next advances the iterator to the next matching quad.
+func (it *quadIterator) Next(ctx context.Context) bool {
+	if err := it.open(ctx); err != nil {
+		return false
+	}
+	for it.src.Next(ctx) {
+		q := it.quadAt()
+		if it.want != nil && quad.StringOf(q.Get(it.filter)) != quad.StringOf(it.want) {
+			continue
+		}
+		it.cur = q
+		return true
+	}
+	it.err = it.src.Err()
+	return false
+}
+
+// NextPath reports additional result paths for the current Result. This
+// iterator has exactly one path per result, so there is never another one.
+func (it *quadIterator) NextPath(ctx context.Context) bool { return false }
+
+// Contains reports whether v's quad would be produced by this iterator,
+// checked directly against the table rather than by scanning to it.
+func (it *quadIterator) Contains(ctx context.Context, v graph.Value) bool {
+	qv, ok := v.(quadValue)
+	if !ok {
+		return false
+	}
+	q := it.qs.Quad(qv)
+	if it.want != nil && quad.StringOf(q.Get(it.filter)) != quad.StringOf(it.want) {
+		return false
+	}
+	tx, err := it.qs.store.Tx(false)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	defer tx.Close()
+	tbl, err := tx.Table(ctx, it.table)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	var key tuple.Key
+	for _, d := range it.order {
+		key = append(key, toSortable(d, q.Get(d)))
+	}
+	for i, p := range it.prefix {
+		if !reflect.DeepEqual(key[i], p) {
+			return false
+		}
+	}
+	if _, err := tbl.GetTuple(ctx, key); err != nil {
+		return false
+	}
+	it.cur = q
+	return true
+}
+
+// Result returns the graph.Value for the quad the iterator currently points
+// at.
+func (it *quadIterator) Result() graph.Value {
+	return quadValue{v: quad.Raw(quad.StringOf(it.cur.Subject)), q: it.cur}
+}
+
+func (it *quadIterator) Err() error { return it.err }
+
+func (it *quadIterator) Close() error {
+	if it.src == nil {
+		return nil
+	}
+	it.src.Close()
+	return it.tx.Close()
+}
+
+// Clone returns a fresh, independent iterator over the same table, order
+// and filter, re-opening its own tuple.Tx the first time it is used.
+func (it *quadIterator) Clone() graph.Iterator {
+	qi := newQuadIteratorOn(it.qs, it.table, it.order, it.prefix, it.filter, it.want)
+	qi.Tagger().CopyFrom(it)
+	return qi
+}
+
+// Size reports that the size of the underlying scan is unknown without
+// running QuadIteratorSize explicitly.
+func (it *quadIterator) Size() (int64, bool) { return -1, false }
+
+// Stats reports rough, non-exact cost estimates for the query optimizer.
+func (it *quadIterator) Stats() graph.IteratorStats {
+	size, _ := it.Size()
+	return graph.IteratorStats{
+		ContainsCost: 1,
+		NextCost:     1,
+		Size:         size,
+	}
+}
+
+// Type identifies this iterator to Cayley's query optimizer.
+func (it *quadIterator) Type() graph.Type { return quadIteratorType }
+
+// Optimize is a no-op: there is no cheaper equivalent plan for a single
+// indexed prefix scan.
+func (it *quadIterator) Optimize() (graph.Iterator, bool) { return it, false }
+
+// SubIterators reports no children: quadIterator is a leaf.
+func (it *quadIterator) SubIterators() []graph.Iterator { return nil }
+
+// Describe reports this iterator's shape for Cayley's query plan dumps.
+func (it *quadIterator) Describe() graph.Description {
+	return graph.Description{
+		UID:  it.UID(),
+		Name: fmt.Sprintf("%s(%s)", it.table, it.prefix),
+		Type: it.Type(),
+	}
+}
+
+func (it *quadIterator) String() string {
+	return fmt.Sprintf("HidalgoTuple(%s)", it.table)
+}
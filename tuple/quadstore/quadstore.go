@@ -0,0 +1,261 @@
+// Package quadstore implements Cayley's graph.QuadStore directly on top of
+// tuple.Store, the same way legacy/nosql bridges a schemaless nosql.Database
+// into Cayley, but built on the typed tuple API instead. Any backend reachable
+// through kv.NewKV + tuple.KV therefore gets a certified Cayley quad store for
+// free, without a per-backend adapter.
+package quadstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cayleygraph/cayley/graph"
+	"github.com/cayleygraph/cayley/graph/iterator"
+	"github.com/cayleygraph/quad"
+
+	"github.com/nwca/hidalgo/tuple"
+	"github.com/nwca/hidalgo/types"
+)
+
+// Table names for the three orderings of the subject/predicate/object/label
+// key, one per primary access pattern a Cayley quad store must support.
+const (
+	TableSPO = "quads_spo"
+	TablePOS = "quads_pos"
+	TableOSP = "quads_osp"
+)
+
+// Type is the name this backend would register under with Cayley's
+// graph.InitQuadStoreFunc registry, and the value QuadStore.Type returns.
+const Type = "tuple"
+
+var fieldTypes = map[string]types.Type{
+	"subject":   types.StringType{},
+	"predicate": types.StringType{},
+	"object":    types.BytesType{},
+	"label":     types.StringType{},
+}
+
+func orderedHeader(name string, order ...string) tuple.Header {
+	h := tuple.Header{Name: name}
+	for _, f := range order {
+		h.Key = append(h.Key, tuple.KeyField{Name: f, Type: fieldTypes[f]})
+	}
+	return h
+}
+
+var (
+	headerSPO = orderedHeader(TableSPO, "subject", "predicate", "object", "label")
+	headerPOS = orderedHeader(TablePOS, "predicate", "object", "subject", "label")
+	headerOSP = orderedHeader(TableOSP, "object", "subject", "predicate", "label")
+)
+
+// New wraps an already-open tuple.Store as a Cayley graph.QuadStore,
+// creating the SPO/POS/OSP index tables if they do not already exist.
+func New(store tuple.Store) (graph.QuadStore, error) {
+	ctx := context.Background()
+	tx, err := store.Tx(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range []tuple.Header{headerSPO, headerPOS, headerOSP} {
+		if _, err := tx.Table(ctx, h.Name); err != nil {
+			if _, err := tx.CreateTable(ctx, h); err != nil {
+				tx.Close()
+				return nil, fmt.Errorf("quadstore: %v", err)
+			}
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &QuadStore{store: store}, nil
+}
+
+// QuadStore implements graph.QuadStore on top of a tuple.Store, keeping the
+// quads in three index tables (SPO, POS, OSP) so that iteration anchored on
+// any one direction can be served as a Table.Scan prefix scan.
+type QuadStore struct {
+	store tuple.Store
+}
+
+func (qs *QuadStore) tables(ctx context.Context, tx tuple.Tx) (spo, pos, osp tuple.Table, err error) {
+	if spo, err = tx.Table(ctx, TableSPO); err != nil {
+		return
+	}
+	if pos, err = tx.Table(ctx, TablePOS); err != nil {
+		return
+	}
+	osp, err = tx.Table(ctx, TableOSP)
+	return
+}
+
+// ApplyDeltas applies a set of quad additions/removals in a single
+// transaction, writing each quad to all three of the SPO/POS/OSP tables.
+func (qs *QuadStore) ApplyDeltas(deltas []graph.Delta, opts graph.IgnoreOpts) error {
+	ctx := context.Background()
+	tx, err := qs.store.Tx(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+	spo, pos, osp, err := qs.tables(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, d := range deltas {
+		s, p, o, l := quadParts(d.Quad)
+		keys := []tuple.Key{
+			{s, p, o, l},
+			{p, o, s, l},
+			{o, s, p, l},
+		}
+		tbls := []tuple.Table{spo, pos, osp}
+		switch d.Action {
+		case graph.Add:
+			for i, tbl := range tbls {
+				if _, err := tbl.InsertTuple(ctx, tuple.Tuple{Key: keys[i]}); err != nil {
+					if opts.IgnoreDup {
+						continue
+					}
+					return err
+				}
+			}
+		case graph.Delete:
+			for i, tbl := range tbls {
+				if err := tbl.DeleteTuple(ctx, keys[i]); err != nil {
+					if opts.IgnoreMissing {
+						continue
+					}
+					return err
+				}
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ValueOf returns the internal Value for a quad.Value, which is simply the
+// value itself: quads are stored using their string/IRI representation as
+// the primary key, so no id translation table is needed.
+func (qs *QuadStore) ValueOf(v quad.Value) graph.Value {
+	if v == nil {
+		return nil
+	}
+	return quadValue{v: v}
+}
+
+// NameOf returns the quad.Value represented by an internal Value.
+func (qs *QuadStore) NameOf(v graph.Value) quad.Value {
+	qv, ok := v.(quadValue)
+	if !ok {
+		return nil
+	}
+	return qv.v
+}
+
+// Quad resolves a quadValue back into a full quad.Quad.
+func (qs *QuadStore) Quad(v graph.Value) quad.Quad {
+	qv, ok := v.(quadValue)
+	if !ok {
+		return quad.Quad{}
+	}
+	return qv.q
+}
+
+// QuadIterator returns an iterator over all quads whose direction d equals v,
+// served as a prefix scan of whichever of the SPO/POS/OSP tables has d as
+// its leading key field.
+func (qs *QuadStore) QuadIterator(d quad.Direction, v graph.Value) graph.Iterator {
+	qv, ok := v.(quadValue)
+	if !ok {
+		return iterator.NewError(fmt.Errorf("quadstore: invalid value %T", v))
+	}
+	return newDirectionIterator(qs, d, qv)
+}
+
+// QuadsAllIterator returns an iterator over every quad in the store.
+func (qs *QuadStore) QuadsAllIterator() graph.Iterator {
+	return newAllIterator(qs, false)
+}
+
+// NodesAllIterator returns an iterator over every distinct node (subject,
+// predicate, object or label value) in the store.
+func (qs *QuadStore) NodesAllIterator() graph.Iterator {
+	return newAllIterator(qs, true)
+}
+
+// Size returns the number of quads in the store, counted off the SPO table
+// (all three index tables always hold the same set of quads).
+func (qs *QuadStore) Size() int64 {
+	ctx := context.Background()
+	tx, err := qs.store.Tx(false)
+	if err != nil {
+		return -1
+	}
+	defer tx.Close()
+	tbl, err := tx.Table(ctx, TableSPO)
+	if err != nil {
+		return -1
+	}
+	it := tbl.Scan(nil)
+	defer it.Close()
+	var n int64
+	for it.Next(ctx) {
+		n++
+	}
+	return n
+}
+
+// Stats reports the store's size. Quads on hidalgo backends are cheap to
+// count exactly, so exact is always honored.
+func (qs *QuadStore) Stats(ctx context.Context, exact bool) (graph.Stats, error) {
+	return graph.Stats{Nodes: graph.Size{Value: qs.Size(), Exact: true}}, nil
+}
+
+// QuadIteratorSize reports the (exact) number of quads QuadIterator(d, v)
+// would yield, by running the matching prefix scan and counting its results.
+func (qs *QuadStore) QuadIteratorSize(ctx context.Context, d quad.Direction, v graph.Value) (graph.Size, error) {
+	qv, ok := v.(quadValue)
+	if !ok {
+		return graph.Size{}, fmt.Errorf("quadstore: invalid value %T", v)
+	}
+	it := newDirectionIterator(qs, d, qv)
+	defer it.Close()
+	var n int64
+	for it.Next(ctx) {
+		n++
+	}
+	if err := it.Err(); err != nil {
+		return graph.Size{}, err
+	}
+	return graph.Size{Value: n, Exact: true}, nil
+}
+
+// Type identifies this backend, as required when a graph.QuadStore
+// implementation is registered with Cayley's graph.InitQuadStoreFunc.
+func (qs *QuadStore) Type() string { return Type }
+
+// QuadDirection returns the value in the given direction of the quad
+// identified by id.
+func (qs *QuadStore) QuadDirection(id graph.Value, d quad.Direction) graph.Value {
+	q := qs.Quad(id)
+	return qs.ValueOf(q.Get(d))
+}
+
+// Close releases resources held by the quad store. The underlying
+// tuple.Store is owned by the caller and is not closed here.
+func (qs *QuadStore) Close() error { return nil }
+
+// quadParts encodes the four directions of a quad as the sortable values
+// used as key fields across the SPO/POS/OSP tables.
+func quadParts(q quad.Quad) (s, p, o, l types.Sortable) {
+	label := ""
+	if q.Label != nil {
+		label = quad.StringOf(q.Label)
+	}
+	return types.String(quad.StringOf(q.Subject)),
+		types.String(quad.StringOf(q.Predicate)),
+		types.Bytes(quad.StringOf(q.Object)),
+		types.String(label)
+}
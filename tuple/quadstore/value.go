@@ -0,0 +1,13 @@
+package quadstore
+
+import "github.com/cayleygraph/quad"
+
+// quadValue is the graph.Value implementation returned by QuadStore. It
+// carries both the resolved quad.Value (for NameOf) and, when known, the
+// full quad it was read from (for Quad/QuadDirection), since quads are
+// addressed directly by their subject/predicate/object/label tuple rather
+// than through a separate id table.
+type quadValue struct {
+	v quad.Value
+	q quad.Quad
+}
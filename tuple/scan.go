@@ -0,0 +1,34 @@
+package tuple
+
+// ScanOptions configures a bounded, optionally reversed scan of a table,
+// as a more general alternative to the plain prefix scan of Table.Scan.
+type ScanOptions struct {
+	// Prefix restricts the scan to keys sharing this prefix, same as the
+	// argument to Table.Scan. It combines with Start/End: both bounds must
+	// also share the prefix.
+	Prefix Key
+	// Start is the lower bound of the scan, or nil for no lower bound.
+	Start Key
+	// StartExclusive excludes Start itself from the results.
+	StartExclusive bool
+	// End is the upper bound of the scan, or nil for no upper bound.
+	End Key
+	// EndExclusive, when true, excludes End itself from the results
+	// (a half-open [Start, End) range). When false, End is included.
+	EndExclusive bool
+	// Reverse returns tuples in descending key order instead of ascending.
+	Reverse bool
+	// Limit caps the number of tuples returned, or 0 for no limit.
+	Limit int
+}
+
+// RangeScanner is implemented by tables that support bounded and reverse
+// scans in addition to the plain prefix scan of Table.Scan. Backends
+// translate ScanOptions into their underlying seek+iterate primitives,
+// honoring the sortable encoding so Reverse yields descending key order.
+//
+// Not every backend implements RangeScanner; callers should type-assert
+// for it and fall back to Table.Scan plus in-memory filtering if absent.
+type RangeScanner interface {
+	ScanRange(opts ScanOptions) Iterator
+}
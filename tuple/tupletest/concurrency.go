@@ -0,0 +1,161 @@
+package tupletest
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nwca/hidalgo/tuple"
+	"github.com/nwca/hidalgo/types"
+)
+
+// concurrency exercises db from many goroutines at once: parallel readers
+// during a writer, N writers to disjoint key ranges, and contended writes to
+// the same key. Same-key write conflicts are first-committer-wins under both
+// tuple.IsolationLevel values, so contended_writes asserts the same thing
+// regardless of which level db reports. Each scenario is wrapped in a
+// goroutine-leak check, so backends that leak goroutines on Tx.Close fail
+// loudly instead of just under contention.
+func concurrency(t testing.TB, db tuple.Store) {
+	ctx := context.TODO()
+	// testing.TB has no Run method; RunTest always passes a *testing.T.
+	run := t.(*testing.T).Run
+
+	setup, err := db.Tx(true)
+	require.NoError(t, err)
+	_, err = setup.CreateTable(ctx, tuple.Header{
+		Name: "test",
+		Key:  []tuple.KeyField{{Name: "k1", Type: types.StringType{}}},
+		Data: []tuple.Field{{Name: "f1", Type: types.IntType{}}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, setup.Commit(ctx))
+
+	run("disjoint_writers", func(t *testing.T) {
+		withGoroutineCheck(t, func() {
+			const n = 8
+			var wg sync.WaitGroup
+			wg.Add(n)
+			errs := make([]error, n)
+			for i := 0; i < n; i++ {
+				go func(i int) {
+					defer wg.Done()
+					errs[i] = writeKey(ctx, db, fmt.Sprintf("disjoint-%d", i), i)
+				}(i)
+			}
+			wg.Wait()
+			for _, err := range errs {
+				require.NoError(t, err)
+			}
+		})
+	})
+
+	run("readers_during_writer", func(t *testing.T) {
+		withGoroutineCheck(t, func() {
+			require.NoError(t, writeKey(ctx, db, "shared", 0))
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 1; i <= 20; i++ {
+					if err := writeKey(ctx, db, "shared", i); err != nil {
+						return
+					}
+				}
+			}()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				tx, err := db.Tx(false)
+				require.NoError(t, err)
+				tbl, err := tx.Table(ctx, "test")
+				require.NoError(t, err)
+				_, err = tbl.GetTuple(ctx, tuple.Key{types.String("shared")})
+				require.NoError(t, err)
+				tx.Close()
+			}
+		})
+	})
+
+	run("contended_writes", func(t *testing.T) {
+		withGoroutineCheck(t, func() {
+			require.NoError(t, writeKey(ctx, db, "contended", 0))
+
+			const n = 8
+			var wg sync.WaitGroup
+			wg.Add(n)
+			errs := make([]error, n)
+			for i := 0; i < n; i++ {
+				go func(i int) {
+					defer wg.Done()
+					errs[i] = writeKey(ctx, db, "contended", i+1)
+				}(i)
+			}
+			wg.Wait()
+
+			var succeeded int
+			for _, err := range errs {
+				if err == nil {
+					succeeded++
+				}
+			}
+			// At least one write must win; both isolation levels treat a
+			// same-key write conflict as first-committer-wins, so a
+			// compliant backend may fail the rest instead of silently
+			// overwriting them.
+			require.GreaterOrEqual(t, succeeded, 1)
+		})
+	})
+}
+
+func writeKey(ctx context.Context, db tuple.Store, key string, n int) error {
+	tx, err := db.Tx(true)
+	if err != nil {
+		return err
+	}
+	tbl, err := tx.Table(ctx, "test")
+	if err != nil {
+		tx.Close()
+		return err
+	}
+	k := tuple.Key{types.String(key)}
+	if _, err := tbl.GetTuple(ctx, k); err != nil {
+		_, err = tbl.InsertTuple(ctx, tuple.Tuple{Key: k, Data: tuple.Data{types.Int(n)}})
+	} else {
+		err = tbl.UpdateTuple(ctx, tuple.Tuple{Key: k, Data: tuple.Data{types.Int(n)}}, nil)
+	}
+	if err != nil {
+		tx.Close()
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// withGoroutineCheck runs fn and asserts it doesn't leak goroutines, with a
+// small tolerance for the test runner's own background goroutines settling.
+func withGoroutineCheck(t *testing.T, fn func()) {
+	runtime.Gosched()
+	before := runtime.NumGoroutine()
+
+	fn()
+
+	var after int
+	for i := 0; i < 50; i++ {
+		runtime.Gosched()
+		after = runtime.NumGoroutine()
+		if after <= before+2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.LessOrEqual(t, after, before+2, "goroutine leak: before=%d after=%d", before, after)
+}
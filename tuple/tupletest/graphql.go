@@ -0,0 +1,72 @@
+package tupletest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nwca/hidalgo/tuple"
+	"github.com/nwca/hidalgo/tuple/graphql"
+	"github.com/nwca/hidalgo/types"
+)
+
+// graphqlTest checks that graphql.NewHandler correctly serves queries and
+// mutations over a table backed by db.
+func graphqlTest(t testing.TB, db tuple.Store) {
+	ctx := context.TODO()
+	header := tuple.Header{
+		Name: "Person",
+		Key: []tuple.KeyField{
+			{Name: "id", Type: types.StringType{}},
+		},
+		Data: []tuple.Field{
+			{Name: "name", Type: types.StringType{}},
+			{Name: "age", Type: types.IntType{}},
+		},
+	}
+
+	tx, err := db.Tx(true)
+	require.NoError(t, err)
+	_, err = tx.CreateTable(ctx, header)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(ctx))
+
+	h, err := graphql.NewHandler(db, header)
+	require.NoError(t, err)
+
+	exec := func(query string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		h.Exec(ctx, w, query, "", nil)
+		var resp struct {
+			Data   map[string]interface{} `json:"data"`
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.NewDecoder(bytes.NewReader(w.Body.Bytes())).Decode(&resp))
+		require.Len(t, resp.Errors, 0, "%v", resp.Errors)
+		return resp.Data
+	}
+
+	exec(`mutation { insertPerson(id: "p1", name: "Ada", age: 30) }`)
+
+	data := exec(`{ Person(id: "p1") { name age } }`)
+	person, ok := data["Person"].(map[string]interface{})
+	require.True(t, ok)
+	require.Equal(t, "Ada", person["name"])
+
+	list := exec(`{ PersonList { id name } }`)
+	items, ok := list["PersonList"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, items, 1)
+
+	exec(`mutation { deletePerson(id: "p1") }`)
+	list = exec(`{ PersonList { id } }`)
+	items, ok = list["PersonList"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, items, 0)
+}
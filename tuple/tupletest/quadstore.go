@@ -0,0 +1,28 @@
+package tupletest
+
+import (
+	"testing"
+
+	"github.com/cayleygraph/cayley/graph"
+	"github.com/cayleygraph/cayley/graph/graphtest"
+
+	"github.com/nwca/hidalgo/tuple/quadstore"
+)
+
+// quadStoreTest plugs tuple/quadstore into Cayley's own graphtest.TestAll
+// conformance suite, so every tuple backend that opts into Options.QuadStore
+// gets certified as a Cayley graph.QuadStore for free.
+func quadStoreTest(t testing.TB, fnc Func) {
+	graphtest.TestAll(t, func(t testing.TB) (graphtest.Config, graph.QuadStore, func()) {
+		db, closer := fnc(t)
+		qs, err := quadstore.New(db)
+		if err != nil {
+			closer()
+			t.Fatal(err)
+		}
+		return graphtest.Config{}, qs, func() {
+			qs.Close()
+			closer()
+		}
+	})
+}
@@ -19,8 +19,28 @@ import (
 // It returns an empty database and a function to destroy it.
 type Func func(t testing.TB) (tuple.Store, func())
 
+// Options controls which optional subtests RunTest runs, for subtests that
+// pull in a heavier dependency than the core conformance suite needs.
+type Options struct {
+	// QuadStore runs the quadstore subtest, which plugs tuple/quadstore
+	// into Cayley's graphtest.TestAll conformance suite.
+	QuadStore bool
+	// GraphQL runs the graphql subtest, which serves queries and mutations
+	// through tuple/graphql.NewHandler.
+	GraphQL bool
+}
+
 // RunTest runs all tests for tuple store implementations.
-func RunTest(t *testing.T, fnc Func) {
+func RunTest(t *testing.T, fnc Func, opts ...Options) {
+	var o Options
+	for _, opt := range opts {
+		if opt.QuadStore {
+			o.QuadStore = true
+		}
+		if opt.GraphQL {
+			o.GraphQL = true
+		}
+	}
 	for _, c := range testList {
 		t.Run(c.name, func(t *testing.T) {
 			db, closer := fnc(t)
@@ -44,6 +64,18 @@ func RunTest(t *testing.T, fnc Func) {
 			}
 		})
 	})
+	if o.QuadStore {
+		t.Run("quadstore", func(t *testing.T) {
+			quadStoreTest(t, fnc)
+		})
+	}
+	if o.GraphQL {
+		t.Run("graphql", func(t *testing.T) {
+			db, closer := fnc(t)
+			defer closer()
+			graphqlTest(t, db)
+		})
+	}
 }
 
 var testList = []struct {
@@ -53,6 +85,9 @@ var testList = []struct {
 	{name: "basic", test: basic},
 	{name: "typed", test: typed},
 	{name: "scans", test: scans},
+	{name: "scans_range", test: scansRange},
+	{name: "indexes", test: indexes},
+	{name: "concurrency", test: concurrency},
 }
 
 func basic(t testing.TB, db tuple.Store) {